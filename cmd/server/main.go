@@ -1,10 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"google.golang.org/grpc"
@@ -13,27 +16,135 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/pratyushpundir/alexandria-services/internal/config"
+	"github.com/pratyushpundir/alexandria-services/internal/logging"
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
 	"github.com/pratyushpundir/alexandria-services/internal/service"
 
 	pb "github.com/pratyushpundir/alexandria-services/gen/ipfs/v1"
 )
 
+// newClusterClient builds a service.ClusterClient from
+// IPFS_CLUSTER_PEERS, a comma-separated list of "<type>:<endpoint>"
+// peers. Supported types are "kubo" (endpoint is the Kubo RPC API base
+// URL) and "blockfrost" (endpoint is the Blockfrost project ID; base
+// and gateway URLs come from the shared Blockfrost config).
+func newClusterClient(cfg *config.Config) (*service.ClusterClient, error) {
+	var backends []service.ClusterBackend
+
+	for i, spec := range strings.Split(cfg.IPFSClusterPeers, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		peerType, endpoint, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid cluster peer %q: want \"<type>:<endpoint>\"", spec)
+		}
+
+		name := fmt.Sprintf("%s-%d", peerType, i)
+		switch peerType {
+		case "kubo":
+			backends = append(backends, service.ClusterBackend{
+				Name: name,
+				Client: service.NewKuboClient(&service.KuboConfig{
+					BaseURL:    endpoint,
+					GatewayURL: cfg.IPFSGatewayURL,
+				}),
+			})
+		case "blockfrost":
+			backends = append(backends, service.ClusterBackend{
+				Name: name,
+				Client: service.NewBlockfrostClient(&service.BlockfrostConfig{
+					ProjectID:  endpoint,
+					BaseURL:    cfg.BlockfrostIPFSBaseURL,
+					GatewayURL: cfg.IPFSGatewayURL,
+				}),
+			})
+		default:
+			return nil, fmt.Errorf("invalid cluster peer %q: unknown type %q (want kubo or blockfrost)", spec, peerType)
+		}
+	}
+
+	return service.NewClusterClient(backends, service.ReplicationPolicy{
+		Min: cfg.IPFSClusterReplicationMin,
+		Max: cfg.IPFSClusterReplicationMax,
+	})
+}
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Create IPFS client - use mock if no Blockfrost credentials provided
+	// Create IPFS client. IPFS_CLUSTER_PEERS takes priority and selects
+	// the cluster backend; otherwise IPFS_BACKEND selects a single
+	// implementation explicitly ("blockfrost", "kubo", "mock"), falling
+	// back to auto-detecting from the presence of Blockfrost credentials
+	// if unset.
+	logger := logging.Default()
+
 	var ipfsClient service.IPFSClient
-	if cfg.BlockfrostProjectID == "" {
-		log.Println("WARNING: No BLOCKFROST_IPFS_PROJECT_ID provided, running in mock mode")
-		ipfsClient = service.NewMockClient(cfg.IPFSGatewayURL)
+	if cfg.IPFSClusterPeers != "" {
+		logger.Info("Using cluster IPFS backend")
+		clusterClient, err := newClusterClient(cfg)
+		if err != nil {
+			log.Fatalf("Failed to configure cluster backend: %v", err)
+		}
+		ipfsClient = clusterClient
 	} else {
-		log.Println("Using Blockfrost IPFS backend")
-		ipfsClient = service.NewBlockfrostClient(&service.BlockfrostConfig{
-			ProjectID:  cfg.BlockfrostProjectID,
-			BaseURL:    cfg.BlockfrostIPFSBaseURL,
-			GatewayURL: cfg.IPFSGatewayURL,
+		backend := cfg.IPFSBackend
+		if backend == "" {
+			if cfg.BlockfrostProjectID == "" {
+				backend = "mock"
+			} else {
+				backend = "blockfrost"
+			}
+		}
+
+		switch backend {
+		case "kubo":
+			logger.Info("Using Kubo IPFS backend")
+			ipfsClient = service.NewKuboClient(&service.KuboConfig{
+				BaseURL:    cfg.KuboAPIBaseURL,
+				GatewayURL: cfg.IPFSGatewayURL,
+			})
+		case "blockfrost":
+			if cfg.BlockfrostProjectID == "" {
+				log.Fatal("IPFS_BACKEND=blockfrost requires BLOCKFROST_IPFS_PROJECT_ID")
+			}
+			logger.Info("Using Blockfrost IPFS backend")
+			ipfsClient = service.NewBlockfrostClient(&service.BlockfrostConfig{
+				ProjectID:  cfg.BlockfrostProjectID,
+				BaseURL:    cfg.BlockfrostIPFSBaseURL,
+				GatewayURL: cfg.IPFSGatewayURL,
+			})
+		case "mock":
+			ipfsClient = service.NewMockClient(cfg.IPFSGatewayURL, cfg.MockStorageDir)
+		default:
+			log.Fatalf("Unknown IPFS_BACKEND %q (want blockfrost, kubo, or mock)", backend)
+		}
+	}
+
+	// Wrap with an LRU + on-disk cache for Get/GetStream if configured,
+	// regardless of which backend was selected above.
+	if cfg.IPFSCacheDir != "" {
+		logger.Info("Caching IPFS reads", "cache_dir", cfg.IPFSCacheDir)
+		cachingClient, err := service.NewCachingClient(ipfsClient, service.CacheConfig{
+			Dir:      cfg.IPFSCacheDir,
+			MaxBytes: cfg.IPFSCacheMaxBytes,
+			TTL:      cfg.IPFSCacheTTL,
 		})
+		if err != nil {
+			log.Fatalf("Failed to configure IPFS cache: %v", err)
+		}
+		ipfsClient = cachingClient
+	}
+
+	// Serve Prometheus metrics on their own listener if configured.
+	var metricsServer *http.Server
+	if cfg.MetricsPort != "" {
+		logger.Info("Serving Prometheus metrics", "port", cfg.MetricsPort)
+		metricsServer = metrics.Serve(":" + cfg.MetricsPort)
 	}
 
 	// Create gRPC server with increased message size for large media uploads (150MB)
@@ -41,6 +152,8 @@ func main() {
 	grpcServer := grpc.NewServer(
 		grpc.MaxRecvMsgSize(maxMsgSize),
 		grpc.MaxSendMsgSize(maxMsgSize),
+		grpc.ChainUnaryInterceptor(service.UnaryLoggingInterceptor),
+		grpc.ChainStreamInterceptor(service.StreamLoggingInterceptor),
 	)
 
 	// Register IPFS service
@@ -64,7 +177,7 @@ func main() {
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("IPFS gRPC server listening on port %s", cfg.GRPCPort)
+		logger.Info("IPFS gRPC server listening", "port", cfg.GRPCPort)
 		if err := grpcServer.Serve(listener); err != nil {
 			log.Fatalf("Failed to serve: %v", err)
 		}
@@ -75,7 +188,10 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
-	log.Println("Shutting down IPFS service...")
+	logger.Info("Shutting down IPFS service...")
 	grpcServer.GracefulStop()
-	log.Println("IPFS service stopped")
+	if metricsServer != nil {
+		metrics.Shutdown(metricsServer)
+	}
+	logger.Info("IPFS service stopped")
 }
@@ -0,0 +1,150 @@
+// Package cid centralizes CID (Content Identifier) construction so that
+// every IPFSClient implementation - mock or real - agrees on the exact
+// bytes a given (version, hash function) pair produces.
+package cid
+
+import (
+	"fmt"
+
+	gocid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Version identifies a CID version, mirroring the IPFS CID spec.
+type Version int
+
+const (
+	// V0 is the legacy base58btc, dag-pb, sha2-256 only CID format.
+	V0 Version = 0
+	// V1 supports configurable codecs and hash functions.
+	V1 Version = 1
+)
+
+// HashFunc names a supported multihash function.
+type HashFunc string
+
+const (
+	SHA2_256   HashFunc = "sha2-256"
+	Blake2b256 HashFunc = "blake2b-256"
+)
+
+// Codec names a supported IPLD codec for CIDv1 CIDs. CIDv0 is always
+// implicitly dag-pb and ignores this field.
+type Codec uint64
+
+const (
+	// Raw stores content as opaque bytes (multicodec 0x55), with no
+	// UnixFS wrapping. Default for CIDv1.
+	Raw Codec = Codec(gocid.Raw)
+	// DagPB is the UnixFS/dag-pb codec (multicodec 0x70) used by Kubo's
+	// default `ipfs add`, where content is wrapped in a UnixFS node.
+	DagPB Codec = Codec(gocid.DagProtobuf)
+)
+
+// Options controls how Sum derives a CID from content.
+type Options struct {
+	Version Version
+	Hash    HashFunc
+	// Codec selects the IPLD codec for CIDv1 (ignored for CIDv0, which
+	// is always dag-pb). The zero value resolves to Raw.
+	Codec Codec
+}
+
+// DefaultOptions matches today's behavior: CIDv0 over sha2-256.
+func DefaultOptions() Options {
+	return Options{Version: V0, Hash: SHA2_256}
+}
+
+// Sum hashes data per opts and returns the resulting CID.
+func Sum(data []byte, opts Options) (gocid.Cid, error) {
+	code, err := hashCode(opts.Hash)
+	if err != nil {
+		return gocid.Undef, err
+	}
+
+	digest, err := mh.Sum(data, code, -1)
+	if err != nil {
+		return gocid.Undef, fmt.Errorf("cid: hashing content: %w", err)
+	}
+
+	switch opts.Version {
+	case V0:
+		if opts.Hash != SHA2_256 && opts.Hash != "" {
+			return gocid.Undef, fmt.Errorf("cid: CIDv0 requires sha2-256, got %q", opts.Hash)
+		}
+		return gocid.NewCidV0(digest), nil
+	case V1:
+		codec := uint64(opts.Codec)
+		if codec == 0 {
+			codec = gocid.Raw
+		}
+		return gocid.NewCidV1(codec, digest), nil
+	default:
+		return gocid.Undef, fmt.Errorf("cid: unsupported version %d", opts.Version)
+	}
+}
+
+// Verify reports whether data hashes to the given CID string under opts.
+func Verify(data []byte, want string, opts Options) (bool, error) {
+	got, err := Sum(data, opts)
+	if err != nil {
+		return false, err
+	}
+	return got.String() == want, nil
+}
+
+// VerifyMatchesCID reports whether data hashes to want, using want's own
+// version/codec/hash-function (decoded from the CID itself) rather than
+// a caller-supplied Options. Useful for cache/replica integrity checks
+// where the original upload options aren't known to the caller.
+func VerifyMatchesCID(data []byte, want string) (bool, error) {
+	parsed, err := gocid.Decode(want)
+	if err != nil {
+		return false, fmt.Errorf("cid: parsing %q: %w", want, err)
+	}
+
+	recomputed, err := parsed.Prefix().Sum(data)
+	if err != nil {
+		return false, fmt.Errorf("cid: rehashing content: %w", err)
+	}
+
+	return recomputed.Equals(parsed), nil
+}
+
+// Validate reports whether s parses as a syntactically valid CID.
+// Callers that derive filesystem paths or other sensitive resources
+// from a caller-supplied CID string (e.g. an on-disk blockstore keyed
+// by CID) must call this first: a CID's charset alone is not enough to
+// rule out path-traversal sequences like "..", but go-cid's decoder
+// rejects anything that isn't a genuine CID (a base58 CIDv0 or a
+// multibase-encoded CIDv1).
+func Validate(s string) error {
+	if _, err := gocid.Decode(s); err != nil {
+		return fmt.Errorf("cid: invalid cid %q: %w", s, err)
+	}
+	return nil
+}
+
+// ParseHashFunc parses the Kubo-style hash function name used on the
+// wire (e.g. the `hash` query parameter of `/api/v0/add`).
+func ParseHashFunc(s string) (HashFunc, error) {
+	switch HashFunc(s) {
+	case "", SHA2_256:
+		return SHA2_256, nil
+	case Blake2b256:
+		return Blake2b256, nil
+	default:
+		return "", fmt.Errorf("cid: unsupported hash function %q", s)
+	}
+}
+
+func hashCode(h HashFunc) (uint64, error) {
+	switch h {
+	case SHA2_256, "":
+		return mh.SHA2_256, nil
+	case Blake2b256:
+		return mh.BLAKE2B_MIN + 31, nil
+	default:
+		return 0, fmt.Errorf("cid: unsupported hash function %q", h)
+	}
+}
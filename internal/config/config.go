@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config holds the IPFS service configuration
@@ -11,6 +12,43 @@ type Config struct {
 	BlockfrostProjectID   string
 	BlockfrostIPFSBaseURL string
 	IPFSGatewayURL        string
+
+	// MetricsPort serves Prometheus /metrics on a separate HTTP
+	// listener. Empty disables it.
+	MetricsPort string
+
+	// IPFSBackend selects which IPFSClient implementation to construct:
+	// "blockfrost", "kubo", or "mock". Empty means auto-detect (today's
+	// behavior: mock unless BlockfrostProjectID is set).
+	IPFSBackend string
+	// KuboAPIBaseURL is the Kubo RPC API used by the "kubo" backend.
+	KuboAPIBaseURL string
+	// MockStorageDir, if set, persists the mock backend's blocks and
+	// pins on disk instead of in memory. Empty keeps the old in-memory
+	// behavior.
+	MockStorageDir string
+
+	// IPFSClusterPeers, if set, selects the cluster backend: a
+	// comma-separated list of "<type>:<endpoint>" peers (type is "kubo"
+	// or "blockfrost"; for "blockfrost", endpoint is the project ID).
+	// Takes priority over IPFSBackend.
+	IPFSClusterPeers string
+	// IPFSClusterReplicationMin is how many peers must confirm an
+	// upload/pin before it's reported successful.
+	IPFSClusterReplicationMin int
+	// IPFSClusterReplicationMax is how many peers to fan an
+	// upload/pin out to. 0 means all configured peers.
+	IPFSClusterReplicationMax int
+
+	// IPFSCacheDir, if set, wraps the selected IPFSClient in an on-disk
+	// LRU cache for Get/GetStream. Empty disables caching.
+	IPFSCacheDir string
+	// IPFSCacheMaxBytes bounds the on-disk cache size; 0 means
+	// unbounded.
+	IPFSCacheMaxBytes int64
+	// IPFSCacheTTL expires cache entries after this long; 0 means
+	// entries never expire on their own (still subject to LRU eviction).
+	IPFSCacheTTL time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -20,6 +58,19 @@ func Load() *Config {
 		BlockfrostProjectID:   getEnv("BLOCKFROST_IPFS_PROJECT_ID", ""),
 		BlockfrostIPFSBaseURL: getEnv("BLOCKFROST_IPFS_BASE_URL", "https://ipfs.blockfrost.io/api/v0"),
 		IPFSGatewayURL:        getEnv("IPFS_GATEWAY_URL", "https://ipfs.blockfrost.dev/ipfs"),
+		MetricsPort:           getEnv("METRICS_PORT", ""),
+
+		IPFSBackend:    getEnv("IPFS_BACKEND", ""),
+		KuboAPIBaseURL: getEnv("KUBO_API_BASE_URL", "http://127.0.0.1:5001"),
+		MockStorageDir: getEnv("MOCK_STORAGE_DIR", ""),
+
+		IPFSClusterPeers:          getEnv("IPFS_CLUSTER_PEERS", ""),
+		IPFSClusterReplicationMin: getEnvInt("IPFS_CLUSTER_REPLICATION_MIN", 1),
+		IPFSClusterReplicationMax: getEnvInt("IPFS_CLUSTER_REPLICATION_MAX", 0),
+
+		IPFSCacheDir:      getEnv("IPFS_CACHE_DIR", ""),
+		IPFSCacheMaxBytes: getEnvInt64("IPFS_CACHE_MAX_BYTES", 0),
+		IPFSCacheTTL:      getEnvDuration("IPFS_CACHE_TTL", 0),
 	}
 }
 
@@ -38,3 +89,21 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
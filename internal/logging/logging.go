@@ -0,0 +1,43 @@
+// Package logging provides a structured (slog) logger and request ID
+// propagation so a slow RPC can be correlated across the gRPC handler
+// and the backend HTTP call it triggers.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+var base = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+type requestIDKey struct{}
+
+// WithRequestID attaches a request ID to ctx for FromContext to pick up.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID attached to ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns a logger annotated with ctx's request ID, falling
+// back to the base logger when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return base.With("request_id", id)
+	}
+	return base
+}
+
+// Default returns the base structured logger, for call sites with no
+// request context (e.g. startup logging).
+func Default() *slog.Logger {
+	return base
+}
@@ -0,0 +1,128 @@
+// Package metrics holds the Prometheus collectors shared by the gRPC
+// server and every IPFSClient backend, plus the HTTP listener that
+// exposes them.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// UploadBytes tracks the size distribution of uploaded content.
+	UploadBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alexandria_ipfs_upload_bytes",
+		Help:    "Size in bytes of content uploaded to IPFS.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 12), // 1KB .. 4GB
+	}, []string{"backend"})
+
+	// RequestDuration tracks latency per backend operation.
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alexandria_ipfs_request_duration_seconds",
+		Help:    "Latency of IPFSClient operations, by backend and operation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend", "operation"})
+
+	// BackendErrors counts failed backend operations by error code.
+	BackendErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alexandria_ipfs_backend_errors_total",
+		Help: "Failed IPFSClient operations, by backend, operation, and error class.",
+	}, []string{"backend", "operation", "code"})
+
+	// CacheEvents counts CachingClient outcomes.
+	CacheEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alexandria_ipfs_cache_events_total",
+		Help: "CachingClient Get outcomes: hit, miss, or coalesced.",
+	}, []string{"result"})
+
+	// GRPCRequestDuration tracks latency of the gRPC handlers themselves.
+	GRPCRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alexandria_ipfs_grpc_request_duration_seconds",
+		Help:    "Latency of IPFSService gRPC RPCs.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// ObserveBackendCall records latency and, on error, a labeled error
+// counter for one IPFSClient backend operation. Call via defer:
+//
+//	defer metrics.ObserveBackendCall(backend, "upload", time.Now(), &err)
+func ObserveBackendCall(backend, operation string, start time.Time, err *error) {
+	RequestDuration.WithLabelValues(backend, operation).Observe(time.Since(start).Seconds())
+	if err != nil && *err != nil {
+		BackendErrors.WithLabelValues(backend, operation, errorCode(*err)).Inc()
+	}
+}
+
+// httpStatusPattern picks the status code out of the "...status %d..."
+// errors every backend formats its non-2xx responses into (see
+// blockfrost.go/kubo.go), without requiring each call site to return a
+// structured error type.
+var httpStatusPattern = regexp.MustCompile(`status (\d{3})`)
+
+// errorCode reports a coarse error class for metrics labels without
+// pulling in the gRPC status package here (backends are plain HTTP
+// clients, not gRPC); it keeps the cardinality fixed regardless of the
+// underlying error's message.
+func errorCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+
+	if m := httpStatusPattern.FindStringSubmatch(err.Error()); m != nil {
+		if code, convErr := strconv.Atoi(m[1]); convErr == nil {
+			switch {
+			case code == http.StatusNotFound:
+				return "not_found"
+			case code >= 500:
+				return "server_error"
+			case code >= 400:
+				return "client_error"
+			}
+		}
+	}
+
+	return "error"
+}
+
+// Serve starts the Prometheus /metrics endpoint on addr in the
+// background. It returns the *http.Server so callers can shut it down.
+func Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		_ = srv.ListenAndServe()
+	}()
+
+	return srv
+}
+
+// Shutdown is a small convenience wrapper so callers don't need to
+// import context just to stop the metrics server.
+func Shutdown(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
@@ -9,8 +9,30 @@ import (
 	"mime/multipart"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
 )
 
+const backendBlockfrost = "blockfrost"
+
+var blockfrostTracer = otel.Tracer("alexandria-ipfs/blockfrost")
+
+// countingReader tracks how many bytes have been read through it, so
+// UploadStream can report upload size to metrics without buffering the
+// whole stream.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // BlockfrostClient handles IPFS operations via Blockfrost API
 type BlockfrostClient struct {
 	projectID  string
@@ -49,36 +71,88 @@ type AddResponse struct {
 	Size     string `json:"size"`
 }
 
-// Upload uploads data to IPFS via Blockfrost
-func (c *BlockfrostClient) Upload(ctx context.Context, data []byte, filename string) (*AddResponse, error) {
-	// Create multipart form
+// buildMultipartUpload wraps data as a single-file multipart/form-data
+// body under the "file" field, the shape every backend's /add-style
+// endpoint expects. BlockfrostClient and KuboClient do not call it; they
+// upload through pipeMultipartUpload, which streams instead of buffering.
+func buildMultipartUpload(data []byte, filename string) (*bytes.Buffer, string, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	part, err := writer.CreateFormFile("file", filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		return nil, "", fmt.Errorf("failed to create form file: %w", err)
 	}
 
 	if _, err := part.Write(data); err != nil {
-		return nil, fmt.Errorf("failed to write data: %w", err)
+		return nil, "", fmt.Errorf("failed to write data: %w", err)
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close writer: %w", err)
+		return nil, "", fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	// Create request
+	return body, writer.FormDataContentType(), nil
+}
+
+// pipeMultipartUpload streams r into a multipart/form-data body over an
+// io.Pipe instead of buffering the whole file, so UploadStream callers
+// don't need the data in memory. It returns the pipe's read side and the
+// content type header, and starts a goroutine that writes the multipart
+// framing plus r's bytes and closes the pipe when done (propagating any
+// write error as the pipe's error so the HTTP request fails cleanly).
+func pipeMultipartUpload(r io.Reader, filename string) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := writer.CreateFormFile("file", filename)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
+			return
+		}
+		if _, err := io.Copy(part, r); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to stream data: %w", err))
+			return
+		}
+		if err := writer.Close(); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to close writer: %w", err))
+			return
+		}
+		pw.Close()
+	}()
+
+	return pr, writer.FormDataContentType()
+}
+
+// Upload uploads data to IPFS via Blockfrost. opts are accepted to
+// satisfy IPFSClient but have no effect: Blockfrost derives the CID
+// server-side and does not expose version/hash-function selection.
+func (c *BlockfrostClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return c.UploadStream(ctx, bytes.NewReader(data), filename, opts...)
+}
+
+// UploadStream streams r to IPFS via Blockfrost without buffering the
+// whole file in memory.
+func (c *BlockfrostClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (addResp *AddResponse, err error) {
+	defer metrics.ObserveBackendCall(backendBlockfrost, "upload", time.Now(), &err)
+
+	ctx, span := blockfrostTracer.Start(ctx, "BlockfrostClient.Upload")
+	defer span.End()
+
+	counted := &countingReader{r: r}
+	body, contentType := pipeMultipartUpload(counted, filename)
+	defer body.Close()
+
 	url := fmt.Sprintf("%s/ipfs/add", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("project_id", c.projectID)
 
-	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload to IPFS: %w", err)
@@ -87,20 +161,38 @@ func (c *BlockfrostClient) Upload(ctx context.Context, data []byte, filename str
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("IPFS upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		err = fmt.Errorf("IPFS upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, err
 	}
 
-	// Parse response
-	var addResp AddResponse
-	if err := json.NewDecoder(resp.Body).Decode(&addResp); err != nil {
+	var result AddResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &addResp, nil
+	metrics.UploadBytes.WithLabelValues(backendBlockfrost).Observe(float64(counted.n))
+	return &result, nil
 }
 
 // Get retrieves content from IPFS via Blockfrost gateway
 func (c *BlockfrostClient) Get(ctx context.Context, cid string) ([]byte, error) {
+	rc, err := c.GetStream(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// GetStream retrieves content from IPFS via Blockfrost gateway without
+// buffering the whole file in memory; the caller must Close the reader.
+func (c *BlockfrostClient) GetStream(ctx context.Context, cid string) (rc io.ReadCloser, err error) {
+	defer metrics.ObserveBackendCall(backendBlockfrost, "get", time.Now(), &err)
+
+	ctx, span := blockfrostTracer.Start(ctx, "BlockfrostClient.Get")
+	defer span.End()
+
 	url := fmt.Sprintf("%s/%s", c.gatewayURL, cid)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -112,17 +204,23 @@ func (c *BlockfrostClient) Get(ctx context.Context, cid string) ([]byte, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get from IPFS: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("IPFS get failed with status %d", resp.StatusCode)
+		defer resp.Body.Close()
+		err = fmt.Errorf("IPFS get failed with status %d", resp.StatusCode)
+		return nil, err
 	}
 
-	return io.ReadAll(resp.Body)
+	return resp.Body, nil
 }
 
 // Pin pins content on Blockfrost IPFS
-func (c *BlockfrostClient) Pin(ctx context.Context, cid string) error {
+func (c *BlockfrostClient) Pin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendBlockfrost, "pin", time.Now(), &err)
+
+	ctx, span := blockfrostTracer.Start(ctx, "BlockfrostClient.Pin")
+	defer span.End()
+
 	url := fmt.Sprintf("%s/ipfs/pin/add/%s", c.baseURL, cid)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
@@ -140,14 +238,20 @@ func (c *BlockfrostClient) Pin(ctx context.Context, cid string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("IPFS pin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		err = fmt.Errorf("IPFS pin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return err
 	}
 
 	return nil
 }
 
 // Unpin removes a pin from Blockfrost IPFS
-func (c *BlockfrostClient) Unpin(ctx context.Context, cid string) error {
+func (c *BlockfrostClient) Unpin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendBlockfrost, "unpin", time.Now(), &err)
+
+	ctx, span := blockfrostTracer.Start(ctx, "BlockfrostClient.Unpin")
+	defer span.End()
+
 	url := fmt.Sprintf("%s/ipfs/pin/remove/%s", c.baseURL, cid)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
@@ -165,7 +269,8 @@ func (c *BlockfrostClient) Unpin(ctx context.Context, cid string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("IPFS unpin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		err = fmt.Errorf("IPFS unpin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return err
 	}
 
 	return nil
@@ -175,3 +280,62 @@ func (c *BlockfrostClient) Unpin(ctx context.Context, cid string) error {
 func (c *BlockfrostClient) GetGatewayURL(cid string) string {
 	return fmt.Sprintf("%s/%s", c.gatewayURL, cid)
 }
+
+// blockfrostPinListPageSize is the page size used when paging through
+// /ipfs/pin/list.
+const blockfrostPinListPageSize = 100
+
+// blockfrostPinEntry is one entry of a /ipfs/pin/list page.
+type blockfrostPinEntry struct {
+	IPFSHash string `json:"ipfs_hash"`
+	State    string `json:"state"`
+}
+
+// ListPins pages through Blockfrost's /ipfs/pin/list, since it has no
+// streaming endpoint, sending one PinInfo per entry as each page
+// arrives rather than buffering the whole pin set.
+func (c *BlockfrostClient) ListPins(ctx context.Context, out chan<- PinInfo) (err error) {
+	defer metrics.ObserveBackendCall(backendBlockfrost, "list_pins", time.Now(), &err)
+
+	ctx, span := blockfrostTracer.Start(ctx, "BlockfrostClient.ListPins")
+	defer span.End()
+
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/ipfs/pin/list?page=%d&count=%d", c.baseURL, page, blockfrostPinListPageSize)
+
+		req, reqErr := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if reqErr != nil {
+			return fmt.Errorf("failed to create request: %w", reqErr)
+		}
+		req.Header.Set("project_id", c.projectID)
+
+		resp, doErr := c.httpClient.Do(req)
+		if doErr != nil {
+			return fmt.Errorf("failed to list pins: %w", doErr)
+		}
+
+		var entries []blockfrostPinEntry
+		decodeErr := json.NewDecoder(resp.Body).Decode(&entries)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("IPFS pin list failed with status %d", resp.StatusCode)
+			return err
+		}
+		if decodeErr != nil {
+			return fmt.Errorf("failed to decode pin list page %d: %w", page, decodeErr)
+		}
+
+		for _, e := range entries {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case out <- PinInfo{CID: e.IPFSHash, Type: e.State}:
+			}
+		}
+
+		if len(entries) < blockfrostPinListPageSize {
+			return nil
+		}
+	}
+}
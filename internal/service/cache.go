@@ -0,0 +1,273 @@
+package service
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/pratyushpundir/alexandria-services/internal/cid"
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
+)
+
+// CacheConfig configures CachingClient.
+type CacheConfig struct {
+	// Dir is where cached blocks are stored, named by CID.
+	Dir string
+	// MaxBytes bounds total on-disk cache size; 0 means unbounded.
+	MaxBytes int64
+	// TTL expires entries after this long since they were cached; 0
+	// means entries only leave via LRU eviction.
+	TTL time.Duration
+}
+
+// CacheStats is a snapshot of CachingClient's counters, also exposed as
+// Prometheus counters (metrics.CacheEvents) for dashboards/alerting.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Coalesced int64
+}
+
+// CachingClient wraps any IPFSClient with an LRU + on-disk cache for
+// Get/GetStream. Because CIDs are content-addressed, every cache hit is
+// verified by re-hashing the cached bytes against the requested CID
+// before being returned, so a corrupted cache file can't silently
+// surface as a corrupted response. Concurrent Get calls for the same
+// CID are coalesced into a single upstream fetch via singleflight.
+type CachingClient struct {
+	IPFSClient
+
+	dir      string
+	maxBytes int64
+	ttl      time.Duration
+
+	group singleflight.Group
+
+	mu       sync.Mutex
+	lru      *list.List // front = most recently used
+	index    map[string]*list.Element
+	curBytes int64
+
+	hits, misses, coalesced atomic.Int64
+}
+
+type cacheEntry struct {
+	cid      string
+	size     int64
+	storedAt time.Time
+}
+
+// NewCachingClient wraps client in an LRU + on-disk cache. If cfg.Dir
+// already contains cached blocks (e.g. across a restart), they're
+// indexed so they remain servable instead of being orphaned.
+func NewCachingClient(client IPFSClient, cfg CacheConfig) (*CachingClient, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("cache: Dir is required")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: creating cache dir: %w", err)
+	}
+
+	c := &CachingClient{
+		IPFSClient: client,
+		dir:        cfg.Dir,
+		maxBytes:   cfg.MaxBytes,
+		ttl:        cfg.TTL,
+		lru:        list.New(),
+		index:      make(map[string]*list.Element),
+	}
+
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("cache: reading cache dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		c.touchLocked(e.Name(), info.Size(), info.ModTime())
+	}
+
+	return c, nil
+}
+
+// Get returns cached content when available and unexpired, otherwise
+// fetches from the wrapped client (coalescing concurrent callers for
+// the same CID) and populates the cache.
+func (c *CachingClient) Get(ctx context.Context, id string) ([]byte, error) {
+	// id ends up as a filesystem path component in readCache/writeCache
+	// below; reject anything that isn't a real CID before it gets near
+	// os.ReadFile/os.WriteFile so a crafted id like "../../etc/passwd"
+	// can't escape c.dir.
+	if err := cid.Validate(id); err != nil {
+		return nil, err
+	}
+
+	if data, ok := c.readCache(id); ok {
+		c.hits.Add(1)
+		metrics.CacheEvents.WithLabelValues("hit").Inc()
+		return data, nil
+	}
+
+	// singleflight reports shared=true to the leader as well whenever
+	// others joined, so track who actually ran the upstream fetch.
+	fetched := false
+	v, err, _ := c.group.Do(id, func() (interface{}, error) {
+		fetched = true
+		data, err := c.IPFSClient.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		c.writeCache(id, data)
+		return data, nil
+	})
+	if !fetched {
+		c.coalesced.Add(1)
+		metrics.CacheEvents.WithLabelValues("coalesced").Inc()
+	} else {
+		c.misses.Add(1)
+		metrics.CacheEvents.WithLabelValues("miss").Inc()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// GetStream serves from the same cache as Get; the cache stores whole
+// blocks, so there is no partial-read path to stream from.
+func (c *CachingClient) GetStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	data, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Stats returns a snapshot of cache hit/miss/coalesced counters.
+func (c *CachingClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
+
+// readCache returns verified, unexpired cached bytes for id, if present.
+func (c *CachingClient) readCache(id string) ([]byte, bool) {
+	c.mu.Lock()
+	elem, ok := c.index[id]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Since(entry.storedAt) > c.ttl {
+		c.removeLocked(elem)
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path(id))
+	if err != nil {
+		c.evict(id)
+		return nil, false
+	}
+
+	ok, err = cid.VerifyMatchesCID(data, id)
+	if err != nil || !ok {
+		// Corrupt or tampered cache entry: drop it and fall back to the
+		// upstream fetch path.
+		c.evict(id)
+		return nil, false
+	}
+
+	return data, true
+}
+
+// writeCache stores data for id and evicts LRU entries until the cache
+// fits within maxBytes.
+func (c *CachingClient) writeCache(id string, data []byte) {
+	path := c.path(id)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.touchLocked(id, int64(len(data)), time.Now())
+	c.evictOverflowLocked()
+	c.mu.Unlock()
+}
+
+// touchLocked inserts or refreshes id at the front of the LRU. Callers
+// must hold c.mu.
+func (c *CachingClient) touchLocked(id string, size int64, storedAt time.Time) {
+	if elem, ok := c.index[id]; ok {
+		c.removeLocked(elem)
+	}
+	entry := &cacheEntry{cid: id, size: size, storedAt: storedAt}
+	c.index[id] = c.lru.PushFront(entry)
+	c.curBytes += size
+}
+
+// removeLocked drops elem from the LRU and index, but not its file.
+// Callers must hold c.mu.
+func (c *CachingClient) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	delete(c.index, entry.cid)
+	c.lru.Remove(elem)
+	c.curBytes -= entry.size
+}
+
+// evictOverflowLocked removes least-recently-used entries (and their
+// files) until curBytes fits within maxBytes. Callers must hold c.mu.
+func (c *CachingClient) evictOverflowLocked() {
+	if c.maxBytes <= 0 {
+		return
+	}
+	for c.curBytes > c.maxBytes {
+		back := c.lru.Back()
+		if back == nil {
+			return
+		}
+		entry := back.Value.(*cacheEntry)
+		c.removeLocked(back)
+		os.Remove(c.path(entry.cid))
+	}
+}
+
+// evict drops id from the cache entirely, including its file.
+func (c *CachingClient) evict(id string) {
+	c.mu.Lock()
+	if elem, ok := c.index[id]; ok {
+		c.removeLocked(elem)
+	}
+	c.mu.Unlock()
+	os.Remove(c.path(id))
+}
+
+func (c *CachingClient) path(id string) string {
+	return filepath.Join(c.dir, id)
+}
+
+// Ensure CachingClient implements IPFSClient
+var _ IPFSClient = (*CachingClient)(nil)
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pratyushpundir/alexandria-services/internal/cid"
+)
+
+// countingClient is an IPFSClient whose Get blocks until release is
+// closed and counts how many times it was actually called, so tests can
+// assert on singleflight coalescing.
+type countingClient struct {
+	calls atomic.Int64
+	data  map[string][]byte
+
+	release chan struct{}
+}
+
+func newCountingClient(data map[string][]byte) *countingClient {
+	return &countingClient{data: data, release: make(chan struct{})}
+}
+
+func (c *countingClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return nil, nil
+}
+func (c *countingClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return nil, nil
+}
+
+func (c *countingClient) Get(ctx context.Context, id string) ([]byte, error) {
+	c.calls.Add(1)
+	<-c.release
+	return c.data[id], nil
+}
+
+func (c *countingClient) GetStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (c *countingClient) Pin(ctx context.Context, id string) error   { return nil }
+func (c *countingClient) Unpin(ctx context.Context, id string) error { return nil }
+func (c *countingClient) GetGatewayURL(id string) string             { return id }
+func (c *countingClient) ListPins(ctx context.Context, out chan<- PinInfo) error {
+	return nil
+}
+
+var _ IPFSClient = (*countingClient)(nil)
+
+func mustSum(t *testing.T, data []byte) string {
+	t.Helper()
+	sum, err := cid.Sum(data, cid.DefaultOptions())
+	if err != nil {
+		t.Fatalf("cid.Sum: %v", err)
+	}
+	return sum.String()
+}
+
+func TestCachingClient_GetCachesAcrossCalls(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	id := mustSum(t, data)
+
+	inner := newCountingClient(map[string][]byte{id: data})
+	close(inner.release) // never blocks in this test
+
+	cache, err := NewCachingClient(inner, CacheConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewCachingClient: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := cache.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get #%d: %v", i, err)
+		}
+		if string(got) != string(data) {
+			t.Fatalf("Get #%d = %q, want %q", i, got, data)
+		}
+	}
+
+	if calls := inner.calls.Load(); calls != 1 {
+		t.Errorf("upstream Get called %d times, want 1 (later calls should hit the cache)", calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 {
+		t.Errorf("Hits = %d, want 2", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("Misses = %d, want 1", stats.Misses)
+	}
+}
+
+func TestCachingClient_CoalescesConcurrentGets(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	id := mustSum(t, data)
+
+	inner := newCountingClient(map[string][]byte{id: data})
+
+	cache, err := NewCachingClient(inner, CacheConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewCachingClient: %v", err)
+	}
+
+	const concurrency = 10
+	var wg sync.WaitGroup
+	errs := make([]error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = cache.Get(context.Background(), id)
+		}(i)
+	}
+
+	// Give every goroutine a chance to enter Get and join the in-flight
+	// singleflight call before releasing the single upstream fetch.
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Get #%d: %v", i, err)
+		}
+	}
+
+	if calls := inner.calls.Load(); calls != 1 {
+		t.Errorf("upstream Get called %d times, want 1 (concurrent Gets should coalesce)", calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Coalesced != concurrency-1 {
+		t.Errorf("Coalesced = %d, want %d", stats.Coalesced, concurrency-1)
+	}
+}
+
+func TestCachingClient_EvictsOverMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+
+	a := []byte("aaaaaaaaaa") // 10 bytes
+	b := []byte("bbbbbbbbbb") // 10 bytes
+	idA := mustSum(t, a)
+	idB := mustSum(t, b)
+
+	inner := newCountingClient(map[string][]byte{idA: a, idB: b})
+	close(inner.release)
+
+	cache, err := NewCachingClient(inner, CacheConfig{Dir: dir, MaxBytes: 15})
+	if err != nil {
+		t.Fatalf("NewCachingClient: %v", err)
+	}
+
+	if _, err := cache.Get(context.Background(), idA); err != nil {
+		t.Fatalf("Get a: %v", err)
+	}
+	if _, err := cache.Get(context.Background(), idB); err != nil {
+		t.Fatalf("Get b: %v", err)
+	}
+
+	// Cache can hold only one of the two 10-byte entries under a 15-byte
+	// cap, so fetching a again should miss the cache and hit upstream.
+	callsBefore := inner.calls.Load()
+	if _, err := cache.Get(context.Background(), idA); err != nil {
+		t.Fatalf("Get a again: %v", err)
+	}
+	if inner.calls.Load() == callsBefore {
+		t.Error("expected the least-recently-used entry to have been evicted, but it was still cached")
+	}
+}
@@ -1,14 +1,78 @@
 package service
 
-import "context"
+import (
+	"context"
+	"io"
+
+	"github.com/pratyushpundir/alexandria-services/internal/cid"
+)
 
 // IPFSClient defines the interface for IPFS operations
 type IPFSClient interface {
-	Upload(ctx context.Context, data []byte, filename string) (*AddResponse, error)
+	Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error)
 	Get(ctx context.Context, cid string) ([]byte, error)
 	Pin(ctx context.Context, cid string) error
 	Unpin(ctx context.Context, cid string) error
 	GetGatewayURL(cid string) string
+
+	// UploadStream behaves like Upload but reads data from r instead of
+	// requiring the whole file in memory, so files larger than the
+	// gRPC max message size can be relayed in chunks.
+	UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (*AddResponse, error)
+	// GetStream behaves like Get but returns a reader the caller drains
+	// (and must Close), rather than buffering the whole file.
+	GetStream(ctx context.Context, cid string) (io.ReadCloser, error)
+
+	// ListPins streams every currently pinned CID to out. The caller
+	// owns out (including its buffering and closing); ListPins only
+	// sends to it and returns once streaming completes or ctx is
+	// cancelled.
+	ListPins(ctx context.Context, out chan<- PinInfo) error
+}
+
+// PinInfo describes one pinned CID, as reported by ListPins. Type is
+// backend-specific and advisory (e.g. Kubo's "recursive"/"direct",
+// Blockfrost's pin state) rather than a value callers should branch on.
+type PinInfo struct {
+	CID  string
+	Type string
+}
+
+// UploadOptions controls CID derivation for an Upload call. The zero
+// value resolves to cid.DefaultOptions() (CIDv0, sha2-256), matching
+// today's behavior.
+type UploadOptions struct {
+	CIDVersion cid.Version
+	HashFunc   cid.HashFunc
+	Codec      cid.Codec
+}
+
+// UploadOption customizes UploadOptions.
+type UploadOption func(*UploadOptions)
+
+// WithCIDVersion selects the CID version produced for an upload.
+func WithCIDVersion(v cid.Version) UploadOption {
+	return func(o *UploadOptions) { o.CIDVersion = v }
+}
+
+// WithHashFunc selects the multihash function used to derive the CID.
+func WithHashFunc(h cid.HashFunc) UploadOption {
+	return func(o *UploadOptions) { o.HashFunc = h }
+}
+
+// WithCodec selects the IPLD codec used for CIDv1 (ignored for CIDv0).
+func WithCodec(c cid.Codec) UploadOption {
+	return func(o *UploadOptions) { o.Codec = c }
+}
+
+// resolveUploadOptions applies opts over cid.DefaultOptions().
+func resolveUploadOptions(opts []UploadOption) cid.Options {
+	resolved := cid.DefaultOptions()
+	o := &UploadOptions{CIDVersion: resolved.Version, HashFunc: resolved.Hash, Codec: resolved.Codec}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return cid.Options{Version: o.CIDVersion, Hash: o.HashFunc, Codec: o.Codec}
 }
 
 // Ensure BlockfrostClient implements IPFSClient
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ClusterBackend pairs a human-readable name with the IPFSClient used
+// to reach one cluster peer (a self-hosted Kubo node, Blockfrost, etc).
+type ClusterBackend struct {
+	Name   string
+	Client IPFSClient
+}
+
+// ReplicationPolicy controls how many ClusterClient backends must agree
+// before an Upload/Pin/Unpin is reported successful, inspired by
+// ipfs-cluster's replication_factor_min/max.
+type ReplicationPolicy struct {
+	// Min is how many backends must confirm before the call succeeds.
+	Min int
+	// Max is how many backends to fan the call out to.
+	Max int
+}
+
+// ClusterClient fans Upload/Pin/Unpin out to a set of IPFSClient
+// backends with a replication factor policy: a call succeeds as soon as
+// Min backends confirm, while the remaining backends (up to Max) keep
+// going in the background.
+type ClusterClient struct {
+	backends []ClusterBackend
+	policy   ReplicationPolicy
+}
+
+// NewClusterClient validates the policy against the backend list and
+// returns a ready-to-use ClusterClient.
+func NewClusterClient(backends []ClusterBackend, policy ReplicationPolicy) (*ClusterClient, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("cluster: at least one backend is required")
+	}
+
+	if policy.Min <= 0 {
+		policy.Min = 1
+	}
+	if policy.Max <= 0 || policy.Max > len(backends) {
+		policy.Max = len(backends)
+	}
+	if policy.Min > policy.Max {
+		return nil, fmt.Errorf("cluster: replication min (%d) exceeds max (%d)", policy.Min, policy.Max)
+	}
+
+	return &ClusterClient{backends: backends, policy: policy}, nil
+}
+
+type clusterUploadResult struct {
+	name string
+	resp *AddResponse
+	err  error
+}
+
+// Upload fans data out to policy.Max backends and returns as soon as
+// policy.Min of them confirm the same CID, verifying the CID matches
+// across peers to detect a corrupted write (as ipfs-cluster does with
+// block/put). The remaining backends are drained in the background so
+// a slow peer doesn't hold up the caller; errors are only aggregated if
+// fewer than Min backends succeed.
+func (c *ClusterClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	results := make(chan clusterUploadResult, c.policy.Max)
+
+	for _, b := range c.backends[:c.policy.Max] {
+		b := b
+		go func() {
+			resp, err := b.Client.Upload(ctx, data, filename, opts...)
+			results <- clusterUploadResult{name: b.Name, resp: resp, err: err}
+		}()
+	}
+
+	var (
+		confirmed []clusterUploadResult
+		errs      []error
+	)
+
+	for len(confirmed) < c.policy.Min && len(confirmed)+len(errs) < c.policy.Max {
+		r := <-results
+		switch {
+		case r.err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+		case len(confirmed) > 0 && r.resp.IPFSHash != confirmed[0].resp.IPFSHash:
+			errs = append(errs, fmt.Errorf("%s: cid mismatch: got %s, expected %s", r.name, r.resp.IPFSHash, confirmed[0].resp.IPFSHash))
+		default:
+			confirmed = append(confirmed, r)
+		}
+	}
+
+	drainClusterResults(results, c.policy.Max-len(confirmed)-len(errs))
+
+	if len(confirmed) < c.policy.Min {
+		return nil, fmt.Errorf("cluster: only %d/%d backends confirmed upload (need %d): %w",
+			len(confirmed), c.policy.Max, c.policy.Min, errors.Join(errs...))
+	}
+
+	return confirmed[0].resp, nil
+}
+
+// UploadStream reads r fully and delegates to Upload: fanning a single
+// stream out to multiple backends requires the bytes more than once, so
+// ClusterClient can't avoid buffering the way a single-backend client
+// can.
+func (c *ClusterClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (*AddResponse, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: reading stream: %w", err)
+	}
+	return c.Upload(ctx, data, filename, opts...)
+}
+
+// Get tries each backend in turn and returns the first successful read,
+// mirroring ipfs-cluster's any-peer-serves-reads model.
+func (c *ClusterClient) Get(ctx context.Context, cid string) ([]byte, error) {
+	var lastErr error
+	for _, b := range c.backends {
+		data, err := b.Client.Get(ctx, cid)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", b.Name, err)
+	}
+	return nil, fmt.Errorf("cluster: no backend has %s: %w", cid, lastErr)
+}
+
+// GetStream tries each backend in turn and returns the first successful
+// stream.
+func (c *ClusterClient) GetStream(ctx context.Context, cid string) (io.ReadCloser, error) {
+	var lastErr error
+	for _, b := range c.backends {
+		rc, err := b.Client.GetStream(ctx, cid)
+		if err == nil {
+			return rc, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", b.Name, err)
+	}
+	return nil, fmt.Errorf("cluster: no backend has %s: %w", cid, lastErr)
+}
+
+// Pin fans out to policy.Max backends and succeeds once policy.Min
+// confirm the pin.
+func (c *ClusterClient) Pin(ctx context.Context, cid string) error {
+	return c.quorum(func(b ClusterBackend) error { return b.Client.Pin(ctx, cid) })
+}
+
+// Unpin fans out to policy.Max backends and succeeds once policy.Min
+// confirm the unpin.
+func (c *ClusterClient) Unpin(ctx context.Context, cid string) error {
+	return c.quorum(func(b ClusterBackend) error { return b.Client.Unpin(ctx, cid) })
+}
+
+// GetGatewayURL returns the first backend's gateway URL.
+func (c *ClusterClient) GetGatewayURL(cid string) string {
+	return c.backends[0].Client.GetGatewayURL(cid)
+}
+
+// ListPins fans out to every backend and merges their pin sets,
+// deduplicating by CID, mirroring ipfs-cluster's cluster-wide "pin ls".
+// Unlike Upload/Pin/Unpin this doesn't apply the replication policy: a
+// CID pinned on just one backend is still reported once.
+func (c *ClusterClient) ListPins(ctx context.Context, out chan<- PinInfo) error {
+	merged := make(chan PinInfo)
+	errCh := make(chan error, len(c.backends))
+
+	var wg sync.WaitGroup
+	for _, b := range c.backends {
+		b := b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.Client.ListPins(ctx, merged); err != nil {
+				errCh <- fmt.Errorf("%s: %w", b.Name, err)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+		close(errCh)
+	}()
+
+	seen := make(map[string]bool)
+	for info := range merged {
+		if seen[info.CID] {
+			continue
+		}
+		seen[info.CID] = true
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- info:
+		}
+	}
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// quorum fans op out to policy.Max backends and waits for policy.Min
+// successes, draining the rest in the background.
+func (c *ClusterClient) quorum(op func(ClusterBackend) error) error {
+	results := make(chan error, c.policy.Max)
+
+	for _, b := range c.backends[:c.policy.Max] {
+		b := b
+		go func() { results <- op(b) }()
+	}
+
+	var (
+		succeeded int
+		errs      []error
+	)
+
+	for succeeded < c.policy.Min && succeeded+len(errs) < c.policy.Max {
+		if err := <-results; err != nil {
+			errs = append(errs, err)
+		} else {
+			succeeded++
+		}
+	}
+
+	drainClusterErrors(results, c.policy.Max-succeeded-len(errs))
+
+	if succeeded < c.policy.Min {
+		return fmt.Errorf("cluster: only %d/%d backends succeeded (need %d): %w",
+			succeeded, c.policy.Max, c.policy.Min, errors.Join(errs...))
+	}
+	return nil
+}
+
+// drainClusterResults discards the n results still pending in the
+// background, after the caller has stopped waiting on slower backends.
+// The results channel is buffered to policy.Max, so a backend's send
+// never blocks whether or not this drain runs.
+func drainClusterResults(results <-chan clusterUploadResult, n int) {
+	if n <= 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			<-results
+		}
+	}()
+}
+
+func drainClusterErrors(results <-chan error, n int) {
+	if n <= 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			<-results
+		}
+	}()
+}
+
+// Ensure ClusterClient implements IPFSClient
+var _ IPFSClient = (*ClusterClient)(nil)
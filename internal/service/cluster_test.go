@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// stubClient is a minimal IPFSClient for exercising ClusterClient's
+// fan-out/quorum logic without a real backend.
+type stubClient struct {
+	uploadCID   string
+	uploadDelay time.Duration
+	uploadErr   error
+
+	getData []byte
+	getErr  error
+
+	pinErr   error
+	unpinErr error
+}
+
+func (s *stubClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	if s.uploadDelay > 0 {
+		select {
+		case <-time.After(s.uploadDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if s.uploadErr != nil {
+		return nil, s.uploadErr
+	}
+	return &AddResponse{IPFSHash: s.uploadCID, Size: "0"}, nil
+}
+
+func (s *stubClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return s.Upload(ctx, nil, filename, opts...)
+}
+
+func (s *stubClient) Get(ctx context.Context, cid string) ([]byte, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	return s.getData, nil
+}
+
+func (s *stubClient) GetStream(ctx context.Context, cid string) (io.ReadCloser, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	return io.NopCloser(bytes.NewReader(s.getData)), nil
+}
+
+func (s *stubClient) Pin(ctx context.Context, cid string) error   { return s.pinErr }
+func (s *stubClient) Unpin(ctx context.Context, cid string) error { return s.unpinErr }
+func (s *stubClient) GetGatewayURL(cid string) string             { return "https://example.invalid/" + cid }
+func (s *stubClient) ListPins(ctx context.Context, out chan<- PinInfo) error {
+	return nil
+}
+
+var _ IPFSClient = (*stubClient)(nil)
+
+func TestNewClusterClient_PolicyNormalization(t *testing.T) {
+	backends := []ClusterBackend{
+		{Name: "a", Client: &stubClient{}},
+		{Name: "b", Client: &stubClient{}},
+		{Name: "c", Client: &stubClient{}},
+	}
+
+	cc, err := NewClusterClient(backends, ReplicationPolicy{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cc.policy.Min != 1 {
+		t.Errorf("Min = %d, want 1 (default)", cc.policy.Min)
+	}
+	if cc.policy.Max != len(backends) {
+		t.Errorf("Max = %d, want %d (all backends)", cc.policy.Max, len(backends))
+	}
+}
+
+func TestNewClusterClient_MinExceedsMax(t *testing.T) {
+	backends := []ClusterBackend{{Name: "a", Client: &stubClient{}}}
+	_, err := NewClusterClient(backends, ReplicationPolicy{Min: 2, Max: 1})
+	if err == nil {
+		t.Fatal("expected an error when Min > Max, got nil")
+	}
+}
+
+func TestNewClusterClient_NoBackends(t *testing.T) {
+	_, err := NewClusterClient(nil, ReplicationPolicy{})
+	if err == nil {
+		t.Fatal("expected an error with zero backends, got nil")
+	}
+}
+
+func TestClusterClient_Upload_SucceedsOnceMinConfirm(t *testing.T) {
+	backends := []ClusterBackend{
+		{Name: "fast-a", Client: &stubClient{uploadCID: "Qm123"}},
+		{Name: "fast-b", Client: &stubClient{uploadCID: "Qm123"}},
+		{Name: "slow", Client: &stubClient{uploadCID: "Qm123", uploadDelay: time.Hour}},
+	}
+
+	cc, err := NewClusterClient(backends, ReplicationPolicy{Min: 2, Max: 3})
+	if err != nil {
+		t.Fatalf("NewClusterClient: %v", err)
+	}
+
+	done := make(chan struct{})
+	var resp *AddResponse
+	go func() {
+		resp, err = cc.Upload(context.Background(), []byte("data"), "file.txt")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Upload did not return once the quorum was met; it waited on the slow backend")
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.IPFSHash != "Qm123" {
+		t.Errorf("IPFSHash = %q, want Qm123", resp.IPFSHash)
+	}
+}
+
+func TestClusterClient_Upload_CIDMismatchCountsAsError(t *testing.T) {
+	backends := []ClusterBackend{
+		{Name: "a", Client: &stubClient{uploadCID: "Qm111"}},
+		{Name: "b", Client: &stubClient{uploadCID: "Qm222"}},
+	}
+
+	cc, err := NewClusterClient(backends, ReplicationPolicy{Min: 2, Max: 2})
+	if err != nil {
+		t.Fatalf("NewClusterClient: %v", err)
+	}
+
+	_, err = cc.Upload(context.Background(), []byte("data"), "file.txt")
+	if err == nil {
+		t.Fatal("expected an error when backends disagree on CID, got nil")
+	}
+}
+
+func TestClusterClient_Upload_FewerThanMinSucceed(t *testing.T) {
+	backends := []ClusterBackend{
+		{Name: "a", Client: &stubClient{uploadCID: "Qm123"}},
+		{Name: "b", Client: &stubClient{uploadErr: errors.New("boom")}},
+	}
+
+	cc, err := NewClusterClient(backends, ReplicationPolicy{Min: 2, Max: 2})
+	if err != nil {
+		t.Fatalf("NewClusterClient: %v", err)
+	}
+
+	_, err = cc.Upload(context.Background(), []byte("data"), "file.txt")
+	if err == nil {
+		t.Fatal("expected an aggregated error when fewer than Min backends succeed, got nil")
+	}
+}
+
+func TestClusterClient_Get_FirstSuccessWins(t *testing.T) {
+	backends := []ClusterBackend{
+		{Name: "a", Client: &stubClient{getErr: errors.New("miss")}},
+		{Name: "b", Client: &stubClient{getData: []byte("hello")}},
+	}
+
+	cc, err := NewClusterClient(backends, ReplicationPolicy{})
+	if err != nil {
+		t.Fatalf("NewClusterClient: %v", err)
+	}
+
+	data, err := cc.Get(context.Background(), "Qm123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("Get returned %q, want %q", data, "hello")
+	}
+}
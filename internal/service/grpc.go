@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 
 	pb "github.com/pratyushpundir/alexandria-api/gen/ipfs/v1"
@@ -10,6 +11,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// streamChunkSize is the frame size used for both UploadContentStream
+// and GetContentStream. 1MB keeps per-message memory bounded while
+// staying well under the 150MB MaxRecvMsgSize cap.
+const streamChunkSize = 1 << 20
+
 // GRPCServer implements the IPFS gRPC service
 type GRPCServer struct {
 	pb.UnimplementedIPFSServiceServer
@@ -71,6 +77,115 @@ func (s *GRPCServer) UploadProto(ctx context.Context, req *pb.UploadProtoRequest
 	}, nil
 }
 
+// UploadContentStream uploads content sent as a sequence of chunks,
+// for files too large for the unary UploadContent RPC's message size
+// cap. The first chunk must carry filename; Data may be empty on that
+// chunk. Requires the IPFSService.UploadContentStream client-streaming
+// RPC and UploadContentChunk message added to the alexandria-api proto.
+func (s *GRPCServer) UploadContentStream(stream pb.IPFSService_UploadContentStreamServer) error {
+	ctx := stream.Context()
+
+	// Receive the first chunk synchronously so filename is known before
+	// UploadStream is called; reading it in the background goroutine
+	// below would race with the call on this goroutine.
+	first, err := stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return status.Error(codes.InvalidArgument, "no chunks received")
+		}
+		return status.Errorf(codes.Internal, "failed to read first chunk: %v", err)
+	}
+
+	filename := first.Filename
+	if filename == "" {
+		filename = "content"
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		if len(first.Data) > 0 {
+			if _, err := pw.Write(first.Data); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			if len(chunk.Data) == 0 {
+				continue
+			}
+			if _, err := pw.Write(chunk.Data); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	result, err := s.client.UploadStream(ctx, pr, filename)
+	if err != nil {
+		pr.CloseWithError(err)
+		return status.Errorf(codes.Internal, "failed to upload content: %v", err)
+	}
+
+	size, _ := strconv.ParseInt(result.Size, 10, 64)
+
+	return stream.SendAndClose(&pb.UploadContentResponse{
+		Cid:       result.IPFSHash,
+		SizeBytes: size,
+	})
+}
+
+// GetContentStream retrieves content from IPFS by CID as a sequence of
+// chunks, for files too large for the unary GetContent RPC's message
+// size cap. Requires the IPFSService.GetContentStream server-streaming
+// RPC and GetContentChunk message added to the alexandria-api proto.
+func (s *GRPCServer) GetContentStream(req *pb.GetContentRequest, stream pb.IPFSService_GetContentStreamServer) error {
+	if req.Cid == "" {
+		return status.Error(codes.InvalidArgument, "cid is required")
+	}
+
+	rc, err := s.client.GetStream(stream.Context(), req.Cid)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "failed to get content: %v", err)
+	}
+	defer rc.Close()
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		default:
+		}
+
+		n, err := rc.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			if sendErr := stream.Send(&pb.GetContentChunk{Data: chunk}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read content: %v", err)
+		}
+	}
+}
+
 // GetContent retrieves content from IPFS by CID
 func (s *GRPCServer) GetContent(ctx context.Context, req *pb.GetContentRequest) (*pb.GetContentResponse, error) {
 	if req.Cid == "" {
@@ -146,3 +261,82 @@ func (s *GRPCServer) GetGatewayURL(ctx context.Context, req *pb.GetGatewayURLReq
 		Url: url,
 	}, nil
 }
+
+// ListPins streams every currently pinned CID, for mirroring/audit
+// tooling that wants the full pin set without loading it all into
+// memory at once. Requires the IPFSService.ListPins server-streaming
+// RPC and ListPinsRequest/PinInfo messages added to the alexandria-api
+// proto.
+func (s *GRPCServer) ListPins(req *pb.ListPinsRequest, stream pb.IPFSService_ListPinsServer) error {
+	listCtx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	pins := make(chan PinInfo)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- s.client.ListPins(listCtx, pins)
+		close(pins)
+	}()
+
+	for info := range pins {
+		if err := stream.Send(&pb.PinInfo{Cid: info.CID, Type: info.Type}); err != nil {
+			// Cancel so ListPins stops early, then drain any pins
+			// already in flight so its goroutine can observe
+			// cancellation and return instead of blocking on a send
+			// nobody reads.
+			cancel()
+			for range pins {
+			}
+			<-errCh
+			return err
+		}
+	}
+
+	if err := <-errCh; err != nil {
+		return status.Errorf(codes.Internal, "failed to list pins: %v", err)
+	}
+	return nil
+}
+
+// PinStatus reports whether a single CID is pinned. It's implemented on
+// top of ListPins (the only pin-enumeration primitive IPFSClient
+// exposes), stopping as soon as a match is found rather than waiting
+// for the full pin set. Requires the IPFSService.PinStatus unary RPC
+// and PinStatusRequest/PinStatusResponse messages added to the
+// alexandria-api proto.
+func (s *GRPCServer) PinStatus(ctx context.Context, req *pb.PinStatusRequest) (*pb.PinStatusResponse, error) {
+	if req.Cid == "" {
+		return nil, status.Error(codes.InvalidArgument, "cid is required")
+	}
+
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pins := make(chan PinInfo)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- s.client.ListPins(listCtx, pins)
+		close(pins)
+	}()
+
+	for info := range pins {
+		if info.CID != req.Cid {
+			continue
+		}
+		// Found it: cancel so ListPins stops early, then drain any pins
+		// already in flight so its goroutine can observe cancellation
+		// and return instead of blocking on a send nobody reads.
+		cancel()
+		for range pins {
+		}
+		<-errCh
+		return &pb.PinStatusResponse{Pinned: true, Type: info.Type}, nil
+	}
+
+	if err := <-errCh; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check pin status: %v", err)
+	}
+	return &pb.PinStatusResponse{Pinned: false}, nil
+}
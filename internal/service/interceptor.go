@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pratyushpundir/alexandria-services/internal/logging"
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
+)
+
+// requestIDMetadataKey is the gRPC metadata key callers may set to
+// correlate a request across services; if absent, RPCs are logged
+// without a request ID rather than one being invented here.
+const requestIDMetadataKey = "x-request-id"
+
+// UnaryLoggingInterceptor propagates an incoming x-request-id metadata
+// value onto the request context (so backend HTTP calls log under the
+// same ID), times the RPC, and records it in GRPCRequestDuration.
+func UnaryLoggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = withRequestIDFromMetadata(ctx)
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err).String()
+	metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+	logging.FromContext(ctx).Info("grpc request",
+		"method", info.FullMethod,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"code", code,
+	)
+
+	return resp, err
+}
+
+// StreamLoggingInterceptor is the streaming-RPC counterpart of
+// UnaryLoggingInterceptor, for UploadContentStream/GetContentStream.
+func StreamLoggingInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withRequestIDFromMetadata(ss.Context())
+	start := time.Now()
+
+	err := handler(srv, &requestIDServerStream{ServerStream: ss, ctx: ctx})
+
+	code := status.Code(err).String()
+	metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+	logging.FromContext(ctx).Info("grpc stream",
+		"method", info.FullMethod,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"code", code,
+	)
+
+	return err
+}
+
+func withRequestIDFromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	vals := md.Get(requestIDMetadataKey)
+	if len(vals) == 0 {
+		return ctx
+	}
+	return logging.WithRequestID(ctx, vals[0])
+}
+
+// requestIDServerStream overrides Context() so handlers observe the
+// request-ID-annotated context instead of the raw stream context.
+type requestIDServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDServerStream) Context() context.Context {
+	return s.ctx
+}
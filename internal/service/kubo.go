@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pratyushpundir/alexandria-services/internal/cid"
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
+)
+
+const backendKubo = "kubo"
+
+// KuboClient talks directly to a Kubo-compatible IPFS HTTP API (the RPC
+// API exposed by kubo/go-ipfs and ipfs-cluster's proxy), so users can
+// self-host without depending on Blockfrost.
+type KuboClient struct {
+	baseURL    string
+	gatewayURL string
+	httpClient *http.Client
+}
+
+// KuboConfig holds Kubo client configuration.
+type KuboConfig struct {
+	// BaseURL is the Kubo RPC API, e.g. http://127.0.0.1:5001
+	BaseURL    string
+	GatewayURL string
+	Timeout    time.Duration
+}
+
+// NewKuboClient creates a new Kubo HTTP API client.
+func NewKuboClient(cfg *KuboConfig) *KuboClient {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &KuboClient{
+		baseURL:    cfg.BaseURL,
+		gatewayURL: cfg.GatewayURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// kuboAddResponse mirrors one line of the newline-delimited JSON that
+// Kubo's /api/v0/add streams back, one object per added file.
+type kuboAddResponse struct {
+	Name string `json:"Name"`
+	Hash string `json:"Hash"`
+	Size string `json:"Size"`
+}
+
+// Upload adds data to IPFS via the Kubo HTTP API.
+func (c *KuboClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return c.UploadStream(ctx, bytes.NewReader(data), filename, opts...)
+}
+
+// UploadStream streams r to IPFS via the Kubo HTTP API without
+// buffering the whole file in memory.
+func (c *KuboClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (result *AddResponse, err error) {
+	defer metrics.ObserveBackendCall(backendKubo, "upload", time.Now(), &err)
+
+	resolved := resolveUploadOptions(opts)
+
+	counted := &countingReader{r: r}
+	body, contentType := pipeMultipartUpload(counted, filename)
+	defer body.Close()
+
+	url := fmt.Sprintf("%s/api/v0/add?cid-version=%d&hash=%s", c.baseURL, resolved.Version, kuboHashName(resolved.Hash))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload to IPFS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("IPFS upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// Kubo streams one JSON object per line; for a single-file upload
+	// the last line is the file itself (earlier lines, if any, describe
+	// intermediate directory wrapping).
+	var addResp kuboAddResponse
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var line_ kuboAddResponse
+		if err := json.Unmarshal(line, &line_); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		addResp = line_
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if addResp.Hash == "" {
+		err = fmt.Errorf("IPFS upload returned no hash")
+		return nil, err
+	}
+
+	metrics.UploadBytes.WithLabelValues(backendKubo).Observe(float64(counted.n))
+
+	return &AddResponse{
+		Name:     addResp.Name,
+		IPFSHash: addResp.Hash,
+		Size:     addResp.Size,
+	}, nil
+}
+
+// Get retrieves content from IPFS via the Kubo HTTP API.
+func (c *KuboClient) Get(ctx context.Context, cid string) ([]byte, error) {
+	rc, err := c.GetStream(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// GetStream retrieves content from IPFS via the Kubo HTTP API without
+// buffering the whole file in memory; the caller must Close the reader.
+func (c *KuboClient) GetStream(ctx context.Context, cid string) (rc io.ReadCloser, err error) {
+	defer metrics.ObserveBackendCall(backendKubo, "get", time.Now(), &err)
+
+	url := fmt.Sprintf("%s/api/v0/cat?arg=%s", c.baseURL, cid)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get from IPFS: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("IPFS get failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Pin pins content via the Kubo HTTP API.
+func (c *KuboClient) Pin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendKubo, "pin", time.Now(), &err)
+
+	url := fmt.Sprintf("%s/api/v0/pin/add?arg=%s", c.baseURL, cid)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to pin: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("IPFS pin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return err
+	}
+
+	return nil
+}
+
+// Unpin removes a pin via the Kubo HTTP API.
+func (c *KuboClient) Unpin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendKubo, "unpin", time.Now(), &err)
+
+	url := fmt.Sprintf("%s/api/v0/pin/rm?arg=%s", c.baseURL, cid)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to unpin: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("IPFS unpin failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return err
+	}
+
+	return nil
+}
+
+// GetGatewayURL returns the public gateway URL for a CID.
+func (c *KuboClient) GetGatewayURL(cid string) string {
+	return fmt.Sprintf("%s/%s", c.gatewayURL, cid)
+}
+
+// kuboPinEntry is one line of the newline-delimited JSON that Kubo's
+// /api/v0/pin/ls streams back when called with stream=true.
+type kuboPinEntry struct {
+	Cid  string `json:"Cid"`
+	Type string `json:"Type"`
+}
+
+// ListPins streams the node's pin set via the Kubo HTTP API's streaming
+// pin/ls, rather than buffering the whole (potentially very large) pin
+// set into one response.
+func (c *KuboClient) ListPins(ctx context.Context, out chan<- PinInfo) (err error) {
+	defer metrics.ObserveBackendCall(backendKubo, "list_pins", time.Now(), &err)
+
+	url := fmt.Sprintf("%s/api/v0/pin/ls?stream=true", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to list pins: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("IPFS pin ls failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry kuboPinEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("failed to decode pin entry: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- PinInfo{CID: entry.Cid, Type: entry.Type}:
+		}
+	}
+	return scanner.Err()
+}
+
+// kuboHashName maps a cid.HashFunc to the name Kubo's `hash` query
+// parameter expects.
+func kuboHashName(h cid.HashFunc) string {
+	if h == "" {
+		return string(cid.SHA2_256)
+	}
+	return string(h)
+}
+
+// Ensure KuboClient implements IPFSClient
+var _ IPFSClient = (*KuboClient)(nil)
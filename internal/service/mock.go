@@ -1,153 +1,208 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"path/filepath"
 	"sync"
-)
-
-// base58 alphabet used by IPFS
-const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
-
-// encodeBase58 encodes bytes to base58 string
-func encodeBase58(input []byte) string {
-	// Count leading zeros
-	leadingZeros := 0
-	for _, b := range input {
-		if b != 0 {
-			break
-		}
-		leadingZeros++
-	}
-
-	// Convert to big integer and encode
-	// Simplified implementation for our mock purposes
-	result := make([]byte, 0, len(input)*2)
-
-	// Work with a copy to avoid modifying input
-	data := make([]byte, len(input))
-	copy(data, input)
+	"time"
 
-	for len(data) > 0 {
-		// Find first non-zero byte
-		firstNonZero := 0
-		for firstNonZero < len(data) && data[firstNonZero] == 0 {
-			firstNonZero++
-		}
-		if firstNonZero == len(data) {
-			break
-		}
-		data = data[firstNonZero:]
-
-		// Divide by 58
-		remainder := 0
-		newData := make([]byte, 0, len(data))
-		for _, b := range data {
-			acc := remainder*256 + int(b)
-			digit := acc / 58
-			remainder = acc % 58
-			if len(newData) > 0 || digit > 0 {
-				newData = append(newData, byte(digit))
-			}
-		}
-		data = newData
-		result = append([]byte{base58Alphabet[remainder]}, result...)
-	}
+	"github.com/pratyushpundir/alexandria-services/internal/cid"
+	"github.com/pratyushpundir/alexandria-services/internal/logging"
+	"github.com/pratyushpundir/alexandria-services/internal/metrics"
+)
 
-	// Add leading '1's for leading zeros in input
-	for i := 0; i < leadingZeros; i++ {
-		result = append([]byte{'1'}, result...)
-	}
+const backendMock = "mock"
 
-	return string(result)
-}
-
-// MockClient is a mock IPFS client for development without Blockfrost credentials
+// MockClient is a mock IPFS client for development without Blockfrost
+// credentials. With storageDir unset it keeps everything in memory, as
+// before; with storageDir set, content and pins survive restarts in an
+// on-disk blockstore (see the "mock storage was likely reset" error
+// this replaces for long-running dev/test deployments).
 type MockClient struct {
 	mu         sync.RWMutex
 	storage    map[string][]byte
 	pins       map[string]bool
 	gatewayURL string
+
+	storageDir string
+	fileLocks  sync.Map // cid -> *sync.Mutex, only used when storageDir != ""
 }
 
-// NewMockClient creates a new mock IPFS client
-func NewMockClient(gatewayURL string) *MockClient {
+// NewMockClient creates a new mock IPFS client. storageDir, if
+// non-empty, switches it to an on-disk blockstore sharded by CID
+// (<dir>/ab/cd/<cid>) plus a JSON pin index, instead of the default
+// in-memory map.
+func NewMockClient(gatewayURL, storageDir string) *MockClient {
 	if gatewayURL == "" {
 		gatewayURL = "https://ipfs.io/ipfs"
 	}
-	log.Println("IPFS service running in MOCK MODE - data is stored in memory only")
-	return &MockClient{
+
+	c := &MockClient{
 		storage:    make(map[string][]byte),
 		pins:       make(map[string]bool),
 		gatewayURL: gatewayURL,
+		storageDir: storageDir,
+	}
+
+	if storageDir == "" {
+		logging.Default().Info("IPFS service running in MOCK MODE - data is stored in memory only")
+		return c
+	}
+
+	if err := os.MkdirAll(storageDir, 0o755); err != nil {
+		logging.Default().Warn("failed to create mock storage dir, falling back to in-memory", "storage_dir", storageDir, "error", err)
+		c.storageDir = ""
+		return c
+	}
+
+	pins, err := loadPinIndex(c.pinIndexPath())
+	if err != nil {
+		logging.Default().Warn("failed to load mock pin index, starting empty", "error", err)
+	} else {
+		c.pins = pins
+	}
+
+	logging.Default().Info("IPFS service running in MOCK MODE - data persisted on disk", "storage_dir", storageDir)
+	return c
+}
+
+// Upload stores data and returns a real CID derived from the content
+// via the cid package, so mock uploads agree with the real backends on
+// CID version and hash function.
+func (c *MockClient) Upload(ctx context.Context, data []byte, filename string, opts ...UploadOption) (*AddResponse, error) {
+	return c.uploadBytes(ctx, data, filename, opts...)
+}
+
+// UploadStream reads r fully and stores it; the mock backend has no
+// benefit from true streaming, but implements the interface so it can
+// stand in for any real backend in tests.
+func (c *MockClient) UploadStream(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (*AddResponse, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
 	}
+	return c.uploadBytes(ctx, data, filename, opts...)
 }
 
-// Upload stores data in memory and returns a fake CID based on content hash
-func (c *MockClient) Upload(ctx context.Context, data []byte, filename string) (*AddResponse, error) {
-	// Generate a valid CIDv0 (base58-encoded multihash)
-	// CIDv0 format: base58(multihash) where multihash = <hash-func-code><digest-length><digest>
-	// For SHA2-256: hash-func-code = 0x12, digest-length = 0x20 (32 bytes)
-	hash := sha256.Sum256(data)
+func (c *MockClient) uploadBytes(ctx context.Context, data []byte, filename string, opts ...UploadOption) (resp *AddResponse, err error) {
+	defer metrics.ObserveBackendCall(backendMock, "upload", time.Now(), &err)
 
-	// Build multihash: 0x12 (sha2-256) + 0x20 (32 bytes) + hash
-	multihash := make([]byte, 34)
-	multihash[0] = 0x12 // sha2-256 function code
-	multihash[1] = 0x20 // digest length (32 bytes)
-	copy(multihash[2:], hash[:])
+	resolved := resolveUploadOptions(opts)
 
-	// Encode as base58 to get valid CIDv0
-	cid := encodeBase58(multihash)
+	sum, err := cid.Sum(data, resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive CID: %w", err)
+	}
+	id := sum.String()
 
-	c.mu.Lock()
-	c.storage[cid] = data
-	c.mu.Unlock()
+	if err = c.store(id, data); err != nil {
+		return nil, err
+	}
 
-	log.Printf("[MOCK IPFS] Uploaded %d bytes as %s (filename: %s)", len(data), cid, filename)
+	metrics.UploadBytes.WithLabelValues(backendMock).Observe(float64(len(data)))
+	logging.FromContext(ctx).Info("mock upload", "cid", id, "bytes", len(data), "filename", filename)
 
 	return &AddResponse{
 		Name:     filename,
-		IPFSHash: cid,
+		IPFSHash: id,
 		Size:     fmt.Sprintf("%d", len(data)),
 	}, nil
 }
 
-// Get retrieves content from memory
-func (c *MockClient) Get(ctx context.Context, cid string) ([]byte, error) {
-	c.mu.RLock()
-	data, ok := c.storage[cid]
-	c.mu.RUnlock()
+// Get retrieves content.
+func (c *MockClient) Get(ctx context.Context, cid string) (data []byte, err error) {
+	defer metrics.ObserveBackendCall(backendMock, "get", time.Now(), &err)
+
+	data, ok, err := c.load(cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", cid, err)
+	}
 
 	if !ok {
-		// Content not found - this happens when mock storage was reset (container restart)
-		// Return an error so the caller knows the content is unavailable
-		log.Printf("[MOCK IPFS] CID %s not found in mock storage", cid)
-		return nil, fmt.Errorf("content not found: %s (mock storage was likely reset)", cid)
+		logging.FromContext(ctx).Warn("mock cid not found", "cid", cid)
+		if c.storageDir == "" {
+			// In-memory mode: most likely the mock storage was reset
+			// (e.g. a container restart), since there's nowhere else
+			// the content could be.
+			err = fmt.Errorf("content not found: %s (mock storage was likely reset)", cid)
+		} else {
+			// On-disk mode: storage persists across restarts, so a miss
+			// means this CID was simply never uploaded here.
+			err = fmt.Errorf("content not found: %s (not present in mock storage)", cid)
+		}
+		return nil, err
 	}
 
-	log.Printf("[MOCK IPFS] Retrieved %d bytes for CID %s", len(data), cid)
+	logging.FromContext(ctx).Info("mock get", "cid", cid, "bytes", len(data))
 	return data, nil
 }
 
-// Pin marks content as pinned in memory
-func (c *MockClient) Pin(ctx context.Context, cid string) error {
+// GetStream returns an in-memory reader over the stored content.
+func (c *MockClient) GetStream(ctx context.Context, cid string) (io.ReadCloser, error) {
+	data, err := c.Get(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Pin marks content as pinned.
+func (c *MockClient) Pin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendMock, "pin", time.Now(), &err)
+
 	c.mu.Lock()
 	c.pins[cid] = true
+	err = c.savePinIndexLocked()
 	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to persist pin index: %w", err)
+	}
 
-	log.Printf("[MOCK IPFS] Pinned CID %s", cid)
+	logging.FromContext(ctx).Info("mock pin", "cid", cid)
 	return nil
 }
 
-// Unpin removes the pin from memory
-func (c *MockClient) Unpin(ctx context.Context, cid string) error {
+// Unpin removes the pin.
+func (c *MockClient) Unpin(ctx context.Context, cid string) (err error) {
+	defer metrics.ObserveBackendCall(backendMock, "unpin", time.Now(), &err)
+
 	c.mu.Lock()
 	delete(c.pins, cid)
+	err = c.savePinIndexLocked()
 	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to persist pin index: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("mock unpin", "cid", cid)
+	return nil
+}
+
+// ListPins streams every pinned CID to out.
+func (c *MockClient) ListPins(ctx context.Context, out chan<- PinInfo) (err error) {
+	defer metrics.ObserveBackendCall(backendMock, "list_pins", time.Now(), &err)
+
+	c.mu.RLock()
+	cids := make([]string, 0, len(c.pins))
+	for id, pinned := range c.pins {
+		if pinned {
+			cids = append(cids, id)
+		}
+	}
+	c.mu.RUnlock()
 
-	log.Printf("[MOCK IPFS] Unpinned CID %s", cid)
+	for _, id := range cids {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- PinInfo{CID: id, Type: "recursive"}:
+		}
+	}
 	return nil
 }
 
@@ -165,15 +220,160 @@ func (c *MockClient) IsPinned(cid string) bool {
 
 // GetStoredCIDs returns all stored CIDs (for testing)
 func (c *MockClient) GetStoredCIDs() []string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if c.storageDir == "" {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
 
-	cids := make([]string, 0, len(c.storage))
-	for cid := range c.storage {
-		cids = append(cids, cid)
+		cids := make([]string, 0, len(c.storage))
+		for cid := range c.storage {
+			cids = append(cids, cid)
+		}
+		return cids
 	}
+
+	var cids []string
+	_ = filepath.WalkDir(c.storageDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Base(path) == pinIndexFilename {
+			return nil
+		}
+		cids = append(cids, filepath.Base(path))
+		return nil
+	})
 	return cids
 }
 
+// store writes data for id, either into the in-memory map or, when
+// storageDir is set, into the sharded on-disk blockstore guarded by a
+// per-CID lock.
+func (c *MockClient) store(id string, data []byte) error {
+	if c.storageDir == "" {
+		c.mu.Lock()
+		c.storage[id] = data
+		c.mu.Unlock()
+		return nil
+	}
+
+	if err := cid.Validate(id); err != nil {
+		return err
+	}
+
+	lock := c.lockFor(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	path := c.blockPath(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create shard dir: %w", err)
+	}
+
+	// Write via a temp file + rename so a crash mid-write can't leave a
+	// truncated block behind.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write block: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to finalize block: %w", err)
+	}
+	return nil
+}
+
+// load reads data for id, reporting whether it exists.
+func (c *MockClient) load(id string) ([]byte, bool, error) {
+	if c.storageDir == "" {
+		c.mu.RLock()
+		data, ok := c.storage[id]
+		c.mu.RUnlock()
+		return data, ok, nil
+	}
+
+	if err := cid.Validate(id); err != nil {
+		return nil, false, err
+	}
+
+	lock := c.lockFor(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	data, err := os.ReadFile(c.blockPath(id))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// lockFor returns the per-CID mutex serializing reads/writes of one
+// block's file, on top of the MockClient-wide RWMutex that guards the
+// pin index and in-memory map.
+func (c *MockClient) lockFor(id string) *sync.Mutex {
+	actual, _ := c.fileLocks.LoadOrStore(id, &sync.Mutex{})
+	return actual.(*sync.Mutex)
+}
+
+// blockPath returns the sharded on-disk path for a CID:
+// <storageDir>/<cid[:2]>/<cid[2:4]>/<cid>.
+func (c *MockClient) blockPath(id string) string {
+	if len(id) < 4 {
+		return filepath.Join(c.storageDir, id)
+	}
+	return filepath.Join(c.storageDir, id[:2], id[2:4], id)
+}
+
+const pinIndexFilename = "pins.json"
+
+func (c *MockClient) pinIndexPath() string {
+	return filepath.Join(c.storageDir, pinIndexFilename)
+}
+
+// savePinIndexLocked persists c.pins as JSON. Callers must hold c.mu.
+func (c *MockClient) savePinIndexLocked() error {
+	if c.storageDir == "" {
+		return nil
+	}
+
+	cids := make([]string, 0, len(c.pins))
+	for cid, pinned := range c.pins {
+		if pinned {
+			cids = append(cids, cid)
+		}
+	}
+
+	data, err := json.Marshal(cids)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.pinIndexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.pinIndexPath())
+}
+
+// loadPinIndex reads a pin index JSON file written by savePinIndexLocked.
+func loadPinIndex(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]bool), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cids []string
+	if err := json.Unmarshal(data, &cids); err != nil {
+		return nil, err
+	}
+
+	pins := make(map[string]bool, len(cids))
+	for _, cid := range cids {
+		pins[cid] = true
+	}
+	return pins, nil
+}
+
 // Ensure MockClient implements IPFSClient
 var _ IPFSClient = (*MockClient)(nil)